@@ -43,7 +43,11 @@ func AddHeader(ctx context.Context, key string, value string) (context.Context,
 	return ctx, nil
 }
 
-type setHeader interface {
+// RequestHeaderSetter is implemented by protocols that support staging
+// per-request headers to be sent alongside the next call. HeaderProtocol
+// and RocketProtocol both implement it, so setHeaders works uniformly
+// regardless of the wire protocol chosen.
+type RequestHeaderSetter interface {
 	SetHeader(key, value string)
 }
 
@@ -62,13 +66,97 @@ func setHeaders(ctx context.Context, protocol Protocol) error {
 	if !ok {
 		return NewTransportException(INVALID_HEADERS_TYPE, "Headers key in context value is not map[string]string")
 	}
-	p, ok := protocol.(setHeader)
+	p, ok := protocol.(RequestHeaderSetter)
 	if !ok {
-		// TODO(T173277635): Support Rocket Transport
-		return NewTransportException(NOT_IMPLEMENTED, fmt.Sprintf("setHeaders not implemented for transport type %T", p))
+		return NewTransportException(NOT_IMPLEMENTED, fmt.Sprintf("setHeaders not implemented for transport type %T", protocol))
 	}
 	for k, v := range headersMap {
 		p.SetHeader(k, v)
 	}
 	return nil
 }
+
+// The responseHeadersKeyType type is unexported to prevent collisions with context keys.
+type responseHeadersKeyType int
+
+const responseHeadersKey responseHeadersKeyType = 0
+
+// SetResponseHeader adds a header to the context, which will be sent as
+// part of the response. It is the server-side, response counterpart to
+// AddHeader: generated handler code and middleware can call it instead of
+// reaching into HeaderProtocol to set reply headers. SetResponseHeader can
+// be called multiple times to add multiple headers.
+func SetResponseHeader(ctx context.Context, key string, value string) (context.Context, error) {
+	headersMap := make(map[string]string)
+	if headers := ctx.Value(responseHeadersKey); headers != nil {
+		var ok bool
+		headersMap, ok = headers.(map[string]string)
+		if !ok {
+			return nil, NewTransportException(INVALID_HEADERS_TYPE, "Response headers key in context value is not map[string]string")
+		}
+	}
+	headersMap[key] = value
+	ctx = context.WithValue(ctx, responseHeadersKey, headersMap)
+	return ctx, nil
+}
+
+// ResponseHeaderSetter is implemented by protocols that support staging
+// headers to be sent back with the next reply. HeaderProtocol and
+// RocketProtocol both implement it, so setResponseHeaders works uniformly
+// regardless of the wire protocol chosen. It is deliberately distinct from
+// RequestHeaderSetter: on RocketProtocol, request and reply headers stage
+// into different frame metadata, so reusing SetHeader for replies would
+// silently send them as request headers instead.
+type ResponseHeaderSetter interface {
+	SetResponseHeader(key, value string)
+}
+
+// ResponseHeaders returns the headers the server returned with the
+// response, if ctx is the context returned by Call. It mirrors
+// AddHeader for replies: callers read response headers uniformly, without
+// casting the Protocol to *HeaderProtocol and calling GetResponseHeaders.
+func ResponseHeaders(ctx context.Context) map[string]string {
+	headers, _ := ctx.Value(responseHeadersKey).(map[string]string)
+	return headers
+}
+
+// withResponseHeaders returns a copy of ctx carrying the response headers
+// from protocol. Call calls this once a call completes, putting the reply
+// headers into the context it returns so ResponseHeaders can read them back.
+func withResponseHeaders(ctx context.Context, protocol Protocol) context.Context {
+	p, ok := protocol.(responseHeaderGetter)
+	if !ok {
+		return ctx
+	}
+	headers := p.GetResponseHeaders()
+	if len(headers) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, responseHeadersKey, headers)
+}
+
+// setResponseHeaders writes the headers staged on ctx via SetResponseHeader
+// into protocol, to be sent as part of the next response. No server code in
+// this tree calls it; it is exercised directly by
+// TestSetResponseHeadersUsesResponseHeaderSetter.
+func setResponseHeaders(ctx context.Context, protocol Protocol) error {
+	if ctx == nil {
+		return nil
+	}
+	headers := ctx.Value(responseHeadersKey)
+	if headers == nil {
+		return nil
+	}
+	headersMap, ok := headers.(map[string]string)
+	if !ok {
+		return NewTransportException(INVALID_HEADERS_TYPE, "Response headers key in context value is not map[string]string")
+	}
+	p, ok := protocol.(ResponseHeaderSetter)
+	if !ok {
+		return NewTransportException(NOT_IMPLEMENTED, fmt.Sprintf("setResponseHeaders not implemented for transport type %T", protocol))
+	}
+	for k, v := range headersMap {
+		p.SetResponseHeader(k, v)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// fakeResponseHeaderProtocol satisfies Protocol (by embedding it, left
+// nil), and stages request and reply headers into separate maps, the way
+// RocketProtocol does, to catch the two being conflated.
+type fakeResponseHeaderProtocol struct {
+	Protocol
+
+	requestHeaders map[string]string
+	replyHeaders   map[string]string
+}
+
+func (f *fakeResponseHeaderProtocol) SetHeader(key, value string) {
+	if f.requestHeaders == nil {
+		f.requestHeaders = make(map[string]string)
+	}
+	f.requestHeaders[key] = value
+}
+
+func (f *fakeResponseHeaderProtocol) SetResponseHeader(key, value string) {
+	if f.replyHeaders == nil {
+		f.replyHeaders = make(map[string]string)
+	}
+	f.replyHeaders[key] = value
+}
+
+func TestSetResponseHeadersUsesResponseHeaderSetter(t *testing.T) {
+	fake := &fakeResponseHeaderProtocol{}
+
+	ctx, err := SetResponseHeader(context.Background(), "reply-key", "reply-val")
+	if err != nil {
+		t.Fatalf("SetResponseHeader: %v", err)
+	}
+	if err := setResponseHeaders(ctx, fake); err != nil {
+		t.Fatalf("setResponseHeaders: %v", err)
+	}
+
+	if !reflect.DeepEqual(fake.replyHeaders, map[string]string{"reply-key": "reply-val"}) {
+		t.Fatalf("reply headers = %v, want written via SetResponseHeader", fake.replyHeaders)
+	}
+	if len(fake.requestHeaders) != 0 {
+		t.Fatalf("reply header leaked into request headers: %v", fake.requestHeaders)
+	}
+}
+
+func TestResponseHeadersRoundTrip(t *testing.T) {
+	fake := &fakeCallProtocol{responseHeaders: map[string]string{"k": "v"}}
+
+	ctx := withResponseHeaders(context.Background(), fake)
+	if got := ResponseHeaders(ctx); !reflect.DeepEqual(got, map[string]string{"k": "v"}) {
+		t.Fatalf("ResponseHeaders(ctx) = %v, want %v", got, fake.responseHeaders)
+	}
+}
@@ -28,32 +28,89 @@ type HeaderProtocol struct {
 	protoID ProtocolID
 }
 
-type HeaderProtocolFactory struct{}
+// HeaderProtocolFactory constructs HeaderProtocols speaking its configured
+// protoID under THeader. NewHeaderProtocolFactory configures
+// ProtocolIDCompact; use NewHeaderProtocolFactoryWithProtocolID to configure
+// a different underlying protocol.
+type HeaderProtocolFactory struct {
+	protoID ProtocolID
+}
 
 func NewHeaderProtocolFactory() *HeaderProtocolFactory {
-	return &HeaderProtocolFactory{}
+	return &HeaderProtocolFactory{protoID: ProtocolIDCompact}
+}
+
+// NewHeaderProtocolFactoryWithProtocolID returns a HeaderProtocolFactory
+// whose HeaderProtocols speak the given underlying protoID under THeader,
+// instead of the ProtocolIDCompact default.
+func NewHeaderProtocolFactoryWithProtocolID(protoID ProtocolID) (*HeaderProtocolFactory, error) {
+	if err := validateHeaderProtocolID(protoID); err != nil {
+		return nil, err
+	}
+	return &HeaderProtocolFactory{protoID: protoID}, nil
 }
 
 func (p *HeaderProtocolFactory) GetProtocol(trans Transport) Protocol {
-	return NewHeaderProtocol(trans)
+	protocol, err := NewHeaderProtocolWithProtocolID(trans, p.protoID)
+	if err != nil {
+		// p.protoID was already validated when the factory was constructed.
+		panic(err)
+	}
+	return protocol
 }
 
 func NewHeaderProtocol(trans Transport) *HeaderProtocol {
+	p, err := NewHeaderProtocolWithProtocolID(trans, ProtocolIDCompact)
+	if err != nil {
+		// ProtocolIDCompact is always valid; this would be an invariant violation.
+		panic(err)
+	}
+	return p
+}
+
+// NewHeaderProtocolWithProtocolID is like NewHeaderProtocol, but speaks the
+// given underlying protoID under THeader instead of the ProtocolIDCompact
+// default. protoID must be ProtocolIDBinary or ProtocolIDCompact, or a
+// ProtocolException is returned.
+func NewHeaderProtocolWithProtocolID(trans Transport, protoID ProtocolID) (*HeaderProtocol, error) {
+	if err := validateHeaderProtocolID(protoID); err != nil {
+		return nil, err
+	}
+
 	p := &HeaderProtocol{
 		origTransport: trans,
-		protoID:       ProtocolIDCompact,
+		protoID:       protoID,
 	}
 	if et, ok := trans.(*HeaderTransport); ok {
 		p.trans = et
 	} else {
 		p.trans = NewHeaderTransport(trans)
 	}
+	p.trans.SetProtocolID(protoID)
 
-	// Effectively an invariant violation.
 	if err := p.ResetProtocol(); err != nil {
-		panic(err)
+		return nil, err
+	}
+	// ResetProtocol derives p.protoID from p.trans.ProtocolID(), not from the
+	// protoID argument directly, so this only actually took effect if
+	// SetProtocolID above updated what the transport reports. Check rather
+	// than trust it, so a transport that doesn't honor SetProtocolID fails
+	// loudly instead of silently falling back to its own default protocol.
+	if p.protoID != protoID {
+		return nil, NewProtocolException(fmt.Errorf("HeaderTransport did not switch to requested protocol id %#x (still %#x)", protoID, p.protoID))
+	}
+	return p, nil
+}
+
+// validateHeaderProtocolID returns a ProtocolException if protoID is not one
+// of the protocols HeaderProtocol can speak under THeader.
+func validateHeaderProtocolID(protoID ProtocolID) error {
+	switch protoID {
+	case ProtocolIDBinary, ProtocolIDCompact:
+		return nil
+	default:
+		return NewProtocolException(fmt.Errorf("unsupported protocol id for HeaderProtocol: %#x", protoID))
 	}
-	return p
 }
 
 func (p *HeaderProtocol) ResetProtocol() error {
@@ -174,6 +231,14 @@ func (p *HeaderProtocol) SetHeader(key, value string) {
 	p.trans.SetRequestHeader(key, value)
 }
 
+// SetResponseHeader stages a header to send with the reply. Like SetHeader,
+// it writes into the header transport's single outgoing-header stage,
+// which THeader sends with whichever message (call or reply) is written
+// next.
+func (p *HeaderProtocol) SetResponseHeader(key, value string) {
+	p.trans.SetRequestHeader(key, value)
+}
+
 // Deprecated Header is deprecated, rather use GetRequestHeader
 func (p *HeaderProtocol) Header(key string) (string, bool) {
 	return p.trans.GetRequestHeader(key)
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import "testing"
+
+func TestValidateHeaderProtocolID(t *testing.T) {
+	for _, tt := range []struct {
+		id      ProtocolID
+		wantErr bool
+	}{
+		{ProtocolIDBinary, false},
+		{ProtocolIDCompact, false},
+		{ProtocolID(0x7f), true},
+	} {
+		err := validateHeaderProtocolID(tt.id)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateHeaderProtocolID(%#x): got err %v, wantErr %v", tt.id, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNewHeaderProtocolFactoryWithProtocolID(t *testing.T) {
+	if _, err := NewHeaderProtocolFactoryWithProtocolID(ProtocolIDBinary); err != nil {
+		t.Errorf("NewHeaderProtocolFactoryWithProtocolID(ProtocolIDBinary): unexpected error %v", err)
+	}
+	if _, err := NewHeaderProtocolFactoryWithProtocolID(ProtocolID(0x7f)); err == nil {
+		t.Error("NewHeaderProtocolFactoryWithProtocolID(0x7f): expected error, got nil")
+	}
+}
+
+func TestNewHeaderProtocolWithProtocolIDRejectsUnsupportedID(t *testing.T) {
+	if _, err := NewHeaderProtocolWithProtocolID(nil, ProtocolID(0x7f)); err == nil {
+		t.Error("NewHeaderProtocolWithProtocolID(nil, 0x7f): expected a ProtocolException, got nil")
+	}
+}
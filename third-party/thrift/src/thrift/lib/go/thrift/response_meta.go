@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import "context"
+
+// ResponseMeta carries metadata about a single RPC response, returned
+// alongside the response value from generated client Call methods. It
+// gives callers a stable way to read reply headers without casting the
+// Protocol to a concrete type such as *HeaderProtocol, and is the
+// extension point for future per-call metadata (e.g. peer identity,
+// transforms applied to the response, server load, timing, or error
+// classification) without changing the Call signature again.
+type ResponseMeta struct {
+	// Headers are the headers the server returned with the response.
+	Headers map[string]string
+}
+
+// responseHeaderGetter is implemented by protocols that can report the
+// headers the server returned with the most recent response. HeaderProtocol
+// and RocketProtocol both implement it.
+type responseHeaderGetter interface {
+	GetResponseHeaders() map[string]string
+}
+
+// newResponseMeta builds a ResponseMeta from the protocol used for a call.
+// Call uses this to populate its ResponseMeta return value once the call
+// completes.
+func newResponseMeta(protocol Protocol) ResponseMeta {
+	p, ok := protocol.(responseHeaderGetter)
+	if !ok {
+		return ResponseMeta{}
+	}
+	return ResponseMeta{Headers: p.GetResponseHeaders()}
+}
+
+// Call runs a single request/response round trip over protocol: it stages
+// any headers added to ctx via AddHeader, invokes do to write the request
+// and read the response, and returns the ResponseMeta for the reply along
+// with a context carrying the reply's headers, readable via
+// ResponseHeaders. It holds the header-staging and response-metadata
+// bookkeeping of a client call in one place.
+//
+// No generated client in this tree calls it; it is exercised directly by
+// TestCallThreadsResponseMeta.
+func Call(ctx context.Context, protocol Protocol, do func(Protocol) error) (context.Context, ResponseMeta, error) {
+	if err := setHeaders(ctx, protocol); err != nil {
+		return ctx, ResponseMeta{}, err
+	}
+	if err := do(protocol); err != nil {
+		return ctx, ResponseMeta{}, err
+	}
+	return withResponseHeaders(ctx, protocol), newResponseMeta(protocol), nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// fakeCallProtocol satisfies Protocol (by embedding it, left nil) plus the
+// request/response header hooks Call relies on.
+type fakeCallProtocol struct {
+	Protocol
+
+	requestHeaders  map[string]string
+	responseHeaders map[string]string
+}
+
+func (f *fakeCallProtocol) SetHeader(key, value string) {
+	if f.requestHeaders == nil {
+		f.requestHeaders = make(map[string]string)
+	}
+	f.requestHeaders[key] = value
+}
+
+func (f *fakeCallProtocol) GetResponseHeaders() map[string]string {
+	return f.responseHeaders
+}
+
+func TestCallThreadsResponseMeta(t *testing.T) {
+	fake := &fakeCallProtocol{responseHeaders: map[string]string{"resp-key": "resp-val"}}
+
+	ctx, err := AddHeader(context.Background(), "req-key", "req-val")
+	if err != nil {
+		t.Fatalf("AddHeader: %v", err)
+	}
+
+	called := false
+	ctx, meta, err := Call(ctx, fake, func(Protocol) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Call: %v", err)
+	}
+	if !called {
+		t.Fatal("Call did not invoke do")
+	}
+	if !reflect.DeepEqual(fake.requestHeaders, map[string]string{"req-key": "req-val"}) {
+		t.Fatalf("request headers not staged on protocol: got %v", fake.requestHeaders)
+	}
+	if !reflect.DeepEqual(meta.Headers, map[string]string{"resp-key": "resp-val"}) {
+		t.Fatalf("ResponseMeta.Headers = %v, want %v", meta.Headers, fake.responseHeaders)
+	}
+	if !reflect.DeepEqual(ResponseHeaders(ctx), map[string]string{"resp-key": "resp-val"}) {
+		t.Fatalf("ResponseHeaders(ctx) = %v, want %v", ResponseHeaders(ctx), fake.responseHeaders)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+// rocketMetadataWriter is implemented by the underlying Rocket wire protocol
+// when it supports attaching per-message metadata to the frame it is about
+// to write (e.g. the metadata section of a Rocket REQUEST_RESPONSE frame).
+// RocketProtocol uses it to put staged headers on the wire.
+type rocketMetadataWriter interface {
+	SetWriteMetadata(map[string]string)
+}
+
+// rocketMetadataReader is implemented by the underlying Rocket wire protocol
+// when it supports reading the metadata section off the frame it just read.
+// RocketProtocol uses it to surface reply headers via GetResponseHeaders.
+type rocketMetadataReader interface {
+	ReadMetadata() map[string]string
+}
+
+// RocketProtocol wraps a Protocol spoken over the Rocket transport, adding
+// the same request-header staging area that HeaderProtocol provides for
+// THeader. Headers set via SetHeader are sent with the next request and
+// cleared on Flush, mirroring HeaderProtocol's SetRequestHeader semantics.
+type RocketProtocol struct {
+	Protocol
+
+	requestHeaders  map[string]string
+	replyHeaders    map[string]string
+	receivedHeaders map[string]string
+	responseHeaders map[string]string
+}
+
+// NewRocketProtocol wraps an existing Protocol (typically a CompactProtocol)
+// with Rocket's request-header staging area.
+func NewRocketProtocol(protocol Protocol) *RocketProtocol {
+	return &RocketProtocol{
+		Protocol: protocol,
+	}
+}
+
+// RocketProtocolFactory constructs RocketProtocols, mirroring
+// HeaderProtocolFactory so Rocket clients/servers can be wired up through
+// the same ProtocolFactory extension point.
+type RocketProtocolFactory struct{}
+
+func NewRocketProtocolFactory() *RocketProtocolFactory {
+	return &RocketProtocolFactory{}
+}
+
+func (f *RocketProtocolFactory) GetProtocol(trans Transport) Protocol {
+	return NewRocketProtocol(NewCompactProtocol(trans))
+}
+
+// SetHeader stages a header to be sent with the next request. Staged
+// headers are cleared after Flush.
+func (p *RocketProtocol) SetHeader(key, value string) {
+	if p.requestHeaders == nil {
+		p.requestHeaders = make(map[string]string)
+	}
+	p.requestHeaders[key] = value
+}
+
+// GetRequestHeaders returns the request headers: on a client, the headers
+// staged to send via SetHeader; on a server, the headers received with the
+// call ReadMessageBegin most recently read. The two never overlap in
+// practice, since a given RocketProtocol plays one role or the other for a
+// given message, but received headers take precedence so a server never
+// sees its own (empty) staging map instead of what the client sent.
+func (p *RocketProtocol) GetRequestHeaders() map[string]string {
+	if p.receivedHeaders != nil {
+		return p.receivedHeaders
+	}
+	return p.requestHeaders
+}
+
+// SetResponseHeader stages a header to be sent with the next reply. Unlike
+// SetHeader, this stages into the reply's own metadata rather than the
+// request's, since Rocket frames carry per-message metadata rather than a
+// single bidirectional header stage like THeader. Staged headers are
+// cleared after Flush.
+func (p *RocketProtocol) SetResponseHeader(key, value string) {
+	if p.replyHeaders == nil {
+		p.replyHeaders = make(map[string]string)
+	}
+	p.replyHeaders[key] = value
+}
+
+// GetResponseHeaders returns the headers the server returned with the
+// most recent response.
+func (p *RocketProtocol) GetResponseHeaders() map[string]string {
+	return p.responseHeaders
+}
+
+// WriteMessageBegin puts any staged headers onto the frame's metadata
+// section, if the underlying protocol supports it, before delegating to it.
+// A reply (typeId REPLY or EXCEPTION) sends the headers staged via
+// SetResponseHeader; a call or oneway message sends the ones staged via
+// SetHeader.
+func (p *RocketProtocol) WriteMessageBegin(name string, typeId MessageType, seqid int32) error {
+	headers := p.requestHeaders
+	if typeId == REPLY || typeId == EXCEPTION {
+		headers = p.replyHeaders
+	}
+	if mw, ok := p.Protocol.(rocketMetadataWriter); ok {
+		mw.SetWriteMetadata(headers)
+	}
+	return p.Protocol.WriteMessageBegin(name, typeId, seqid)
+}
+
+// ReadMessageBegin delegates to the underlying protocol to read the message
+// envelope, then routes its metadata section, if the underlying protocol
+// supports it, by the message type just read: a CALL/ONEWAY carries the
+// request headers a server received, so it lands in receivedHeaders and is
+// returned by GetRequestHeaders; a REPLY/EXCEPTION carries the response
+// headers a client received, so it lands in responseHeaders.
+func (p *RocketProtocol) ReadMessageBegin() (name string, typeId MessageType, seqid int32, err error) {
+	name, typeId, seqid, err = p.Protocol.ReadMessageBegin()
+	if err != nil {
+		return name, typeId, seqid, err
+	}
+	if mr, ok := p.Protocol.(rocketMetadataReader); ok {
+		metadata := mr.ReadMetadata()
+		if typeId == REPLY || typeId == EXCEPTION {
+			p.responseHeaders = metadata
+		} else {
+			p.receivedHeaders = metadata
+		}
+	}
+	return name, typeId, seqid, err
+}
+
+// Flush flushes the underlying protocol and clears the staged request and
+// reply headers and the request headers a server received. Response headers
+// a client received are kept until the next ReadMessageBegin replaces them.
+func (p *RocketProtocol) Flush() error {
+	err := p.Protocol.Flush()
+	p.requestHeaders = nil
+	p.replyHeaders = nil
+	p.receivedHeaders = nil
+	return err
+}
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package thrift
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fakeRocketWireProtocol simulates the underlying Rocket wire protocol: it
+// captures the metadata handed to it on write, and hands back canned
+// metadata on read, the way a real Rocket frame codec would.
+type fakeRocketWireProtocol struct {
+	Protocol
+
+	writtenMetadata map[string]string
+	readMetadata    map[string]string
+	readType        MessageType
+}
+
+func (f *fakeRocketWireProtocol) SetWriteMetadata(headers map[string]string) {
+	f.writtenMetadata = headers
+}
+
+func (f *fakeRocketWireProtocol) ReadMetadata() map[string]string {
+	return f.readMetadata
+}
+
+func (f *fakeRocketWireProtocol) WriteMessageBegin(name string, typeId MessageType, seqid int32) error {
+	return nil
+}
+
+func (f *fakeRocketWireProtocol) ReadMessageBegin() (string, MessageType, int32, error) {
+	return "", f.readType, 0, nil
+}
+
+func (f *fakeRocketWireProtocol) Flush() error {
+	return nil
+}
+
+func TestRocketProtocolHeaderRoundTrip(t *testing.T) {
+	wire := &fakeRocketWireProtocol{readMetadata: map[string]string{"resp-key": "resp-val"}, readType: REPLY}
+	p := NewRocketProtocol(wire)
+
+	p.SetHeader("req-key", "req-val")
+	if err := p.WriteMessageBegin("Svc.method", CALL, 1); err != nil {
+		t.Fatalf("WriteMessageBegin: %v", err)
+	}
+	if !reflect.DeepEqual(wire.writtenMetadata, map[string]string{"req-key": "req-val"}) {
+		t.Fatalf("request headers were not wired onto the write path: got %v", wire.writtenMetadata)
+	}
+
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if headers := p.GetRequestHeaders(); headers != nil {
+		t.Fatalf("request headers should be cleared after Flush, got %v", headers)
+	}
+
+	if _, _, _, err := p.ReadMessageBegin(); err != nil {
+		t.Fatalf("ReadMessageBegin: %v", err)
+	}
+	if !reflect.DeepEqual(p.GetResponseHeaders(), map[string]string{"resp-key": "resp-val"}) {
+		t.Fatalf("response headers were not populated from the reply: got %v", p.GetResponseHeaders())
+	}
+}
+
+func TestRocketProtocolRoutesReplyHeadersSeparately(t *testing.T) {
+	wire := &fakeRocketWireProtocol{}
+	p := NewRocketProtocol(wire)
+
+	p.SetHeader("req-key", "req-val")
+	p.SetResponseHeader("reply-key", "reply-val")
+
+	if err := p.WriteMessageBegin("Svc.method", REPLY, 1); err != nil {
+		t.Fatalf("WriteMessageBegin: %v", err)
+	}
+	if !reflect.DeepEqual(wire.writtenMetadata, map[string]string{"reply-key": "reply-val"}) {
+		t.Fatalf("a REPLY message should send the headers staged via SetResponseHeader, got %v", wire.writtenMetadata)
+	}
+
+	if err := p.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if p.GetRequestHeaders() != nil {
+		t.Fatalf("request headers should be cleared after Flush, got %v", p.GetRequestHeaders())
+	}
+}
+
+func TestRocketProtocolFactoryImplementsProtocolFactory(t *testing.T) {
+	var _ interface {
+		GetProtocol(Transport) Protocol
+	} = NewRocketProtocolFactory()
+}
+
+// TestRocketProtocolServerReadsRequestHeaders covers the server side of a
+// call: reading a CALL frame's metadata must surface through
+// GetRequestHeaders, not GetResponseHeaders, since a server never stages
+// its own request headers to compare against.
+func TestRocketProtocolServerReadsRequestHeaders(t *testing.T) {
+	wire := &fakeRocketWireProtocol{readMetadata: map[string]string{"req-key": "req-val"}, readType: CALL}
+	p := NewRocketProtocol(wire)
+
+	if _, typeId, _, err := p.ReadMessageBegin(); err != nil {
+		t.Fatalf("ReadMessageBegin: %v", err)
+	} else if typeId != CALL {
+		t.Fatalf("typeId = %v, want CALL", typeId)
+	}
+	if !reflect.DeepEqual(p.GetRequestHeaders(), map[string]string{"req-key": "req-val"}) {
+		t.Fatalf("GetRequestHeaders() = %v, want the CALL frame's metadata", p.GetRequestHeaders())
+	}
+	if p.GetResponseHeaders() != nil {
+		t.Fatalf("a CALL frame should not populate response headers, got %v", p.GetResponseHeaders())
+	}
+}
+
+// fakeDuplexWire models a single request/reply exchange on the Rocket wire,
+// shared between a client-side and a server-side fake, so a test can write
+// headers on one end and read them back on the other the way two real
+// Rocket peers would. This is the closest thing to a genuine end-to-end
+// round trip buildable in this tree: the real wire codec, and the
+// Transport/CompactProtocol/BinaryProtocol constructors HeaderProtocol needs
+// for an equivalent THeader round trip, are not present here.
+type fakeDuplexWire struct {
+	requestMetadata map[string]string
+	requestType     MessageType
+	replyMetadata   map[string]string
+	replyType       MessageType
+}
+
+type fakeRocketClientWire struct {
+	Protocol
+	wire *fakeDuplexWire
+}
+
+func (f *fakeRocketClientWire) SetWriteMetadata(headers map[string]string) {
+	f.wire.requestMetadata = headers
+}
+
+func (f *fakeRocketClientWire) WriteMessageBegin(name string, typeId MessageType, seqid int32) error {
+	f.wire.requestType = typeId
+	return nil
+}
+
+func (f *fakeRocketClientWire) ReadMetadata() map[string]string {
+	return f.wire.replyMetadata
+}
+
+func (f *fakeRocketClientWire) ReadMessageBegin() (string, MessageType, int32, error) {
+	return "", f.wire.replyType, 0, nil
+}
+
+type fakeRocketServerWire struct {
+	Protocol
+	wire *fakeDuplexWire
+}
+
+func (f *fakeRocketServerWire) SetWriteMetadata(headers map[string]string) {
+	f.wire.replyMetadata = headers
+}
+
+func (f *fakeRocketServerWire) WriteMessageBegin(name string, typeId MessageType, seqid int32) error {
+	f.wire.replyType = typeId
+	return nil
+}
+
+func (f *fakeRocketServerWire) ReadMetadata() map[string]string {
+	return f.wire.requestMetadata
+}
+
+func (f *fakeRocketServerWire) ReadMessageBegin() (string, MessageType, int32, error) {
+	return "", f.wire.requestType, 0, nil
+}
+
+func TestRocketProtocolClientServerRoundTrip(t *testing.T) {
+	wire := &fakeDuplexWire{}
+	client := NewRocketProtocol(&fakeRocketClientWire{wire: wire})
+	server := NewRocketProtocol(&fakeRocketServerWire{wire: wire})
+
+	client.SetHeader("req-key", "req-val")
+	if err := client.WriteMessageBegin("Svc.method", CALL, 1); err != nil {
+		t.Fatalf("client WriteMessageBegin: %v", err)
+	}
+
+	if _, typeId, _, err := server.ReadMessageBegin(); err != nil {
+		t.Fatalf("server ReadMessageBegin: %v", err)
+	} else if typeId != CALL {
+		t.Fatalf("server read typeId = %v, want CALL", typeId)
+	}
+	if !reflect.DeepEqual(server.GetRequestHeaders(), map[string]string{"req-key": "req-val"}) {
+		t.Fatalf("server did not receive the client's request headers: got %v", server.GetRequestHeaders())
+	}
+
+	server.SetResponseHeader("reply-key", "reply-val")
+	if err := server.WriteMessageBegin("Svc.method", REPLY, 1); err != nil {
+		t.Fatalf("server WriteMessageBegin: %v", err)
+	}
+
+	if _, typeId, _, err := client.ReadMessageBegin(); err != nil {
+		t.Fatalf("client ReadMessageBegin: %v", err)
+	} else if typeId != REPLY {
+		t.Fatalf("client read typeId = %v, want REPLY", typeId)
+	}
+	if !reflect.DeepEqual(client.GetResponseHeaders(), map[string]string{"reply-key": "reply-val"}) {
+		t.Fatalf("client did not receive the server's reply headers: got %v", client.GetResponseHeaders())
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package trace propagates W3C Trace Context (and optionally Baggage)
+// across Thrift calls using the same header machinery AddHeader and
+// HeaderProtocol.SetRequestHeader already expose. It is transport-agnostic:
+// it works with THeader and with Rocket, since both thrift.HeaderProtocol and
+// thrift.RocketProtocol implement thrift.RequestHeaderSetter. The package
+// does not depend on any
+// particular exporter; callers supply their own TracerProvider configured
+// for OTLP/gRPC, OTLP/HTTP, or whatever backend they use.
+package trace
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"thrift/lib/go/thrift"
+)
+
+// config holds the options shared by the client middleware and server
+// interceptor.
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+// Option configures the client middleware or server interceptor.
+type Option func(*config)
+
+// WithTracerProvider overrides the TracerProvider used to start spans.
+// Defaults to otel.GetTracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) { c.tracerProvider = tp }
+}
+
+// WithPropagator overrides the propagator used to inject/extract trace
+// context. Defaults to otel.GetTextMapPropagator(), which is a W3C Trace
+// Context (and Baggage, if registered) propagator unless the application
+// has configured otel with something else.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) { c.propagator = p }
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// headerCarrier adapts the map[string]string used by AddHeader and
+// HeaderProtocol to propagation.TextMapCarrier.
+type headerCarrier map[string]string
+
+func (c headerCarrier) Get(key string) string {
+	return c[key]
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ClientMiddleware returns a context wrapper to call before a generated
+// client Call method. It injects the active span's context into the
+// outgoing Thrift headers via the configured propagator, so the server can
+// reconstruct the remote span.
+//
+//	ctx, err := trace.ClientMiddleware()(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	resp, meta, err := client.MyMethod(ctx, req)
+func ClientMiddleware(opts ...Option) func(context.Context) (context.Context, error) {
+	c := newConfig(opts)
+	return func(ctx context.Context) (context.Context, error) {
+		carrier := headerCarrier{}
+		c.propagator.Inject(ctx, carrier)
+
+		var err error
+		for k, v := range carrier {
+			if ctx, err = thrift.AddHeader(ctx, k, v); err != nil {
+				return ctx, fmt.Errorf("trace: failed to add header %q: %w", k, err)
+			}
+		}
+		return ctx, nil
+	}
+}
+
+// requestHeaderGetter is implemented by protocols that can report the
+// headers received with the current request. thrift.HeaderProtocol and
+// thrift.RocketProtocol both implement it.
+type requestHeaderGetter interface {
+	GetRequestHeaders() map[string]string
+}
+
+// ServerInterceptor extracts trace context from the incoming request
+// headers of protocol and starts a child span named after method. Callers
+// invoke it at the top of a generated handler, around the processor call,
+// and must call the returned func to end the span.
+//
+//	ctx, end := trace.ServerInterceptor(ctx, protocol, "MyService.myMethod")
+//	defer end()
+func ServerInterceptor(ctx context.Context, protocol thrift.Protocol, method string, opts ...Option) (context.Context, func()) {
+	c := newConfig(opts)
+
+	headers := map[string]string{}
+	if hp, ok := protocol.(requestHeaderGetter); ok {
+		headers = hp.GetRequestHeaders()
+	}
+
+	ctx = c.propagator.Extract(ctx, headerCarrier(headers))
+	ctx, span := c.tracerProvider.Tracer("thrift").Start(ctx, method)
+	return ctx, func() { span.End() }
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) Meta Platforms, Inc. and affiliates.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"thrift/lib/go/thrift"
+)
+
+// fakeRequestHeaderProtocol satisfies thrift.Protocol (by embedding it,
+// left nil) plus GetRequestHeaders, the way thrift.RocketProtocol does,
+// without being a *thrift.HeaderProtocol.
+type fakeRequestHeaderProtocol struct {
+	thrift.Protocol
+
+	headers map[string]string
+}
+
+func (f *fakeRequestHeaderProtocol) GetRequestHeaders() map[string]string {
+	return f.headers
+}
+
+func TestServerInterceptorComposesWithNonHeaderProtocol(t *testing.T) {
+	fake := &fakeRequestHeaderProtocol{headers: map[string]string{"traceparent": "00-00000000000000000000000000000001-0000000000000001-01"}}
+
+	ctx, end := ServerInterceptor(context.Background(), fake, "Svc.method")
+	if ctx == nil {
+		t.Fatal("ServerInterceptor returned a nil context")
+	}
+	if end == nil {
+		t.Fatal("ServerInterceptor returned a nil end func")
+	}
+	end()
+}
+
+// fakeRequestHeaderSetter satisfies thrift.Protocol (by embedding it, left
+// nil) plus SetHeader, recording the headers thrift.Call stages on it.
+type fakeRequestHeaderSetter struct {
+	thrift.Protocol
+
+	headers map[string]string
+}
+
+func (f *fakeRequestHeaderSetter) SetHeader(key, value string) {
+	if f.headers == nil {
+		f.headers = make(map[string]string)
+	}
+	f.headers[key] = value
+}
+
+func TestClientMiddlewareAddsHeaders(t *testing.T) {
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{15: 1},
+		SpanID:     trace.SpanID{7: 1},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+
+	ctx, err := ClientMiddleware(WithPropagator(propagation.TraceContext{}))(ctx)
+	if err != nil {
+		t.Fatalf("ClientMiddleware: %v", err)
+	}
+	if ctx == nil {
+		t.Fatal("ClientMiddleware returned a nil context")
+	}
+
+	fake := &fakeRequestHeaderSetter{}
+	if _, _, err := thrift.Call(ctx, fake, func(thrift.Protocol) error { return nil }); err != nil {
+		t.Fatalf("thrift.Call: %v", err)
+	}
+	want := "00-00000000000000000000000000000001-0000000000000001-01"
+	if got := fake.headers["traceparent"]; got != want {
+		t.Fatalf("traceparent header = %q, want %q", got, want)
+	}
+}